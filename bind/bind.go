@@ -0,0 +1,185 @@
+// Package bind populates a struct from an incoming HTTP request and
+// reports any failure as a *problem.ValidationProblem, so a handler can
+// just write:
+//
+//	var req myRequest
+//	if err := bind.Bind(r, &req); err != nil {
+//		return err
+//	}
+package bind
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+// Bind populates dst, a pointer to a struct, from r. For GET and DELETE
+// requests it binds from r.URL.Query(); for other methods it dispatches
+// on r's Content-Type, supporting application/json, application/xml (or
+// text/xml), application/x-www-form-urlencoded and
+// multipart/form-data. A missing or unrecognized Content-Type on those
+// methods is a binding failure, not a silent no-op. After binding, if
+// DefaultValidator is set, dst is passed to it for struct-tag
+// validation.
+//
+// Binding or validation failures are returned as a
+// *problem.ValidationProblem, ready to be returned from a
+// webapputil.Handle handler. Query and form bindings list every invalid
+// field; a JSON or XML decode failure is reported as a single entry for
+// the first error the decoder returns.
+func Bind(r *http.Request, dst interface{}) error {
+	var err error
+	switch r.Method {
+	case http.MethodGet, http.MethodDelete:
+		err = bindQuery(r.URL.Query(), dst)
+	default:
+		err = bindBody(r, dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	if DefaultValidator != nil {
+		if err := DefaultValidator.Validate(dst); err != nil {
+			return validationProblemFromValidateError(err)
+		}
+	}
+	return nil
+}
+
+func bindBody(r *http.Request, dst interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	switch mediaType {
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+			return validationProblemFromDecodeError(err)
+		}
+		return nil
+	case "application/xml", "text/xml":
+		if err := xml.NewDecoder(r.Body).Decode(dst); err != nil {
+			return validationProblemFromDecodeError(err)
+		}
+		return nil
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return invalidBodyProblem(err)
+		}
+		return bindQuery(r.Form, dst)
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return invalidBodyProblem(err)
+		}
+		return bindQuery(r.Form, dst)
+	default:
+		return problem.NewValidation(problem.InvalidParam{
+			Name:   "Content-Type",
+			Reason: fmt.Sprintf("unsupported or missing media type %q", mediaType),
+		})
+	}
+}
+
+// bindQuery binds values onto the exported fields of dst (a pointer to a
+// struct) by matching each field's "json" tag name, falling back to its
+// Go name, and converting the first matching value to the field's type.
+func bindQuery(values url.Values, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: dst must be a pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	var invalid []problem.InvalidParam
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		if err := setField(rv.Field(i), raw); err != nil {
+			invalid = append(invalid, problem.InvalidParam{
+				Name:   name,
+				Reason: err.Error(),
+			})
+		}
+	}
+
+	if len(invalid) > 0 {
+		return problem.NewValidation(invalid...)
+	}
+	return nil
+}
+
+func setField(fv reflect.Value, raw string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setField(fv.Elem(), raw)
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer, got %q", raw)
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", raw)
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be a boolean, got %q", raw)
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Kind())
+	}
+	return nil
+}
+
+func invalidBodyProblem(err error) error {
+	return problem.NewValidation(problem.InvalidParam{Reason: err.Error()})
+}
+
+// validationProblemFromDecodeError converts a JSON or XML decode error
+// into a *problem.ValidationProblem, extracting the offending field name
+// and value from a *json.UnmarshalTypeError when possible.
+func validationProblemFromDecodeError(err error) error {
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+		return problem.NewValidation(problem.InvalidParam{
+			Name:   typeErr.Field,
+			Reason: fmt.Sprintf("must be a %s, got %s", typeErr.Type, typeErr.Value),
+		})
+	}
+	return problem.NewValidation(problem.InvalidParam{Reason: err.Error()})
+}
@@ -0,0 +1,77 @@
+package bind
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+func TestBindQueryParams(t *testing.T) {
+	type query struct {
+		Name string `json:"name"`
+		Page *int   `json:"page"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?name=foo&page=2", nil)
+	var dst query
+	if err := Bind(r, &dst); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if dst.Name != "foo" {
+		t.Errorf("Name = %q, want %q", dst.Name, "foo")
+	}
+	if dst.Page == nil || *dst.Page != 2 {
+		t.Errorf("Page = %v, want pointer to 2", dst.Page)
+	}
+}
+
+func TestBindQueryInvalidInt(t *testing.T) {
+	type query struct {
+		Page int `json:"page"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?page=notanumber", nil)
+	var dst query
+	err := Bind(r, &dst)
+	if err == nil {
+		t.Fatal("Bind: got nil error, want a validation problem")
+	}
+	if _, ok := err.(*problem.ValidationProblem); !ok {
+		t.Fatalf("Bind error type = %T, want *problem.ValidationProblem", err)
+	}
+}
+
+func TestBindJSONBody(t *testing.T) {
+	type body struct {
+		Name string `json:"name"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"foo"}`))
+	r.Header.Set("Content-Type", "application/json")
+	var dst body
+	if err := Bind(r, &dst); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if dst.Name != "foo" {
+		t.Errorf("Name = %q, want %q", dst.Name, "foo")
+	}
+}
+
+func TestBindMissingContentTypeIsRejected(t *testing.T) {
+	type body struct {
+		Name string `json:"name"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"foo"}`))
+	var dst body
+	err := Bind(r, &dst)
+	if err == nil {
+		t.Fatal("Bind: got nil error for missing Content-Type, want a validation problem")
+	}
+	if dst.Name != "" {
+		t.Errorf("Name = %q, want the body to be left unbound", dst.Name)
+	}
+}
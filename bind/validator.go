@@ -0,0 +1,66 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+// Validator validates a struct bound by Bind, returning a non-nil error
+// if validation fails. Implement this to plug in a validation library.
+type Validator interface {
+	Validate(v interface{}) error
+}
+
+// ValidatorFunc adapts a plain function to Validator, for example
+// (*validator.Validate).Struct from github.com/go-playground/validator:
+//
+//	validate := validator.New()
+//	bind.DefaultValidator = bind.ValidatorFunc(validate.Struct)
+type ValidatorFunc func(v interface{}) error
+
+// Validate calls f.
+func (f ValidatorFunc) Validate(v interface{}) error {
+	return f(v)
+}
+
+// DefaultValidator, if non-nil, is used by Bind to validate dst after
+// binding. It is nil by default, which skips validation.
+var DefaultValidator Validator
+
+// fieldError matches the shape of github.com/go-playground/validator/v10's
+// FieldError, letting validationProblemFromValidateError extract
+// per-field invalid-params without an explicit dependency on that
+// package.
+type fieldError interface {
+	Field() string
+	Tag() string
+}
+
+// validationProblemFromValidateError converts the error returned by
+// DefaultValidator.Validate into a *problem.ValidationProblem. If err is
+// a slice of values each implementing fieldError (as
+// validator.ValidationErrors is), one invalid-params entry is produced
+// per element; otherwise err's message is used as a single entry.
+func validationProblemFromValidateError(err error) error {
+	rv := reflect.ValueOf(err)
+	if rv.Kind() == reflect.Slice {
+		invalid := make([]problem.InvalidParam, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			fe, ok := rv.Index(i).Interface().(fieldError)
+			if !ok {
+				invalid = nil
+				break
+			}
+			invalid = append(invalid, problem.InvalidParam{
+				Name:   fe.Field(),
+				Reason: fmt.Sprintf("failed %q validation", fe.Tag()),
+			})
+		}
+		if invalid != nil {
+			return problem.NewValidation(invalid...)
+		}
+	}
+	return problem.NewValidation(problem.InvalidParam{Reason: err.Error()})
+}
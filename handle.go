@@ -0,0 +1,88 @@
+package webapputil
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+// HandlerFunc is like http.HandlerFunc, but additionally returns an
+// error, letting a handler report failures by returning them instead of
+// writing a response itself.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+type handleConfig struct {
+	logger Logger
+}
+
+// HandleOption configures Handle.
+type HandleOption func(*handleConfig)
+
+// WithHandleLogger sets the logger used to log unmapped errors. It
+// defaults to the standard library's log package, mirroring
+// WithRecoverLogger.
+func WithHandleLogger(logger Logger) HandleOption {
+	return func(c *handleConfig) { c.logger = logger }
+}
+
+// Handle adapts fn to an http.Handler. When the error fn returns is or
+// wraps a problem.Error (via errors.As), the status code and body from
+// its ProblemDetail are sent via problem.SendProblemForRequest, which
+// adds the request ID extension when one is available. When it wraps problem.ErrNotFound or
+// problem.ErrValidation (via errors.Is), it is mapped to a canned RFC
+// 7807 response with the matching status code. Any other error is
+// treated as an unexpected failure, logged, and reported as a 500
+// problem, the same way RecoverMiddleware reports a panic.
+func Handle(fn HandlerFunc, opts ...HandleOption) http.Handler {
+	c := &handleConfig{
+		logger: log.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err == nil {
+			return
+		}
+
+		var perr problem.Error
+		if errors.As(err, &perr) {
+			statusCode, body := perr.ProblemDetail()
+			sendProblem(w, r, c.logger, statusCode, body)
+			return
+		}
+
+		switch {
+		case errors.Is(err, problem.ErrNotFound):
+			sendCannedProblem(w, r, c.logger, http.StatusNotFound, err.Error())
+		case errors.Is(err, problem.ErrValidation):
+			sendCannedProblem(w, r, c.logger, http.StatusBadRequest, err.Error())
+		default:
+			// err here hasn't opted in to being client-facing (it doesn't
+			// implement problem.Error and isn't one of the sentinels
+			// above), so its message may contain details like DSNs or
+			// internal paths. Log it and send a generic detail instead.
+			c.logger.Printf("unhandled error from webapputil.Handle; %v", err)
+			sendCannedProblem(w, r, c.logger, http.StatusInternalServerError, "")
+		}
+	})
+}
+
+func sendCannedProblem(w http.ResponseWriter, r *http.Request, logger Logger, statusCode int, detail string) {
+	sendProblem(w, r, logger, statusCode, problem.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: detail,
+	})
+}
+
+func sendProblem(w http.ResponseWriter, r *http.Request, logger Logger, statusCode int, body interface{}) {
+	if err := problem.SendProblemForRequest(w, r, statusCode, body); err != nil {
+		logger.Printf("failed to send problem response; %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package webapputil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+func TestHandleProblemError(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) error {
+		return problem.Wrap(errors.New("already exists"), http.StatusConflict, "Conflict")
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	if body["title"] != "Conflict" {
+		t.Errorf("title = %v, want %q", body["title"], "Conflict")
+	}
+	if body["detail"] != "already exists" {
+		t.Errorf("detail = %v, want %q", body["detail"], "already exists")
+	}
+}
+
+func TestHandleSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "not found", err: fmt.Errorf("user 42: %w", problem.ErrNotFound), want: http.StatusNotFound},
+		{name: "validation", err: fmt.Errorf("age: %w", problem.ErrValidation), want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Handle(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleUnmappedErrorDoesNotLeakMessage(t *testing.T) {
+	logger := &testLogger{}
+	h := Handle(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New(`db dial tcp 10.1.2.3:5432: password authentication failed for user "admin"`)
+	}, WithHandleLogger(logger))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	if detail, _ := body["detail"].(string); detail != "" {
+		t.Errorf("detail = %q, want empty so the raw error message isn't sent to the client", detail)
+	}
+	if len(logger.lines) == 0 {
+		t.Error("expected the unmapped error to be logged, got no log lines")
+	}
+}
@@ -0,0 +1,95 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error is implemented by errors that know how to render themselves as an
+// RFC 7807 problem response. webapputil.Handle uses it to send the
+// response for an error returned by a handler.
+type Error interface {
+	error
+	ProblemDetail() (statusCode int, body interface{})
+}
+
+// ErrNotFound and ErrValidation are sentinel errors that
+// webapputil.Handle maps to canned RFC 7807 responses when an error
+// wraps them (via errors.Is) without implementing Error itself.
+var (
+	ErrNotFound   = errors.New("problem: not found")
+	ErrValidation = errors.New("problem: validation failed")
+)
+
+// InvalidParam describes one invalid request parameter, in the form
+// shown in the "invalid-params" example in RFC 7807 §3.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ValidationProblem is the problem detail built by NewValidation and
+// returned by webapputil/bind's Bind.
+type ValidationProblem struct {
+	Problem
+	InvalidParams []InvalidParam `json:"invalid-params"`
+}
+
+// NewValidation builds a *ValidationProblem listing invalidParams. The
+// returned value implements Error, so webapputil.Handle sends it with its
+// invalid-params when a handler returns it. It also wraps ErrValidation,
+// so errors.Is(err, ErrValidation) reports true for it.
+func NewValidation(invalidParams ...InvalidParam) *ValidationProblem {
+	return &ValidationProblem{
+		Problem: Problem{
+			Type:   "about:blank",
+			Title:  "Your request parameters didn't validate.",
+			Status: http.StatusBadRequest,
+		},
+		InvalidParams: invalidParams,
+	}
+}
+
+func (p *ValidationProblem) Error() string {
+	return p.Title
+}
+
+func (p *ValidationProblem) Unwrap() error {
+	return ErrValidation
+}
+
+func (p *ValidationProblem) ProblemDetail() (statusCode int, body interface{}) {
+	return http.StatusBadRequest, p
+}
+
+// wrapError adapts a plain error to Error by pairing it with a status
+// code and title, as constructed by Wrap.
+type wrapError struct {
+	err        error
+	statusCode int
+	title      string
+}
+
+// Wrap returns an error implementing Error, so that webapputil.Handle
+// sends it as an "about:blank" problem with the given statusCode and
+// title, and err's message as Detail.
+func Wrap(err error, statusCode int, title string) error {
+	return &wrapError{err: err, statusCode: statusCode, title: title}
+}
+
+func (e *wrapError) Error() string {
+	return e.err.Error()
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+func (e *wrapError) ProblemDetail() (statusCode int, body interface{}) {
+	return e.statusCode, Problem{
+		Type:   "about:blank",
+		Title:  e.title,
+		Status: e.statusCode,
+		Detail: e.err.Error(),
+	}
+}
@@ -0,0 +1,126 @@
+package problem
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder encodes v, a problem detail value, to w.
+type Encoder func(w io.Writer, v interface{}) error
+
+var (
+	encodersMu   sync.RWMutex
+	encoders     = map[string]Encoder{}
+	encoderOrder []string
+)
+
+func init() {
+	RegisterEncoder(contentType, func(w io.Writer, v interface{}) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+	RegisterEncoder("application/problem+xml", func(w io.Writer, v interface{}) error {
+		return xml.NewEncoder(w).Encode(v)
+	})
+}
+
+// RegisterEncoder registers enc as the encoder to use for mediaType when
+// negotiating a representation in SendProblemNegotiated. Calling
+// RegisterEncoder with a mediaType that is already registered replaces its
+// encoder without changing its negotiation priority.
+func RegisterEncoder(mediaType string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	if _, ok := encoders[mediaType]; !ok {
+		encoderOrder = append(encoderOrder, mediaType)
+	}
+	encoders[mediaType] = enc
+}
+
+// SendProblemNegotiated writes a problem response in the representation
+// requested by r's Accept header, chosen among the encoders registered
+// with RegisterEncoder. It falls back to application/problem+json when
+// the Accept header is absent, malformed, or matches no registered
+// encoder.
+func SendProblemNegotiated(w http.ResponseWriter, r *http.Request, statusCode int, problem interface{}) error {
+	mediaType, enc := negotiateEncoder(r.Header.Get("Accept"))
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(statusCode)
+	return enc(w, problem)
+}
+
+func negotiateEncoder(accept string) (string, Encoder) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	for _, rng := range parseAccept(accept) {
+		if rng.q == 0 {
+			// q=0 means "not acceptable" (RFC 7231 §5.3.1); never match it.
+			continue
+		}
+		if enc, ok := encoders[rng.mediaType]; ok {
+			return rng.mediaType, enc
+		}
+		if rng.mediaType == "*/*" && len(encoderOrder) > 0 {
+			mt := encoderOrder[0]
+			return mt, encoders[mt]
+		}
+		if prefix := strings.TrimSuffix(rng.mediaType, "/*"); prefix != rng.mediaType {
+			for _, mt := range encoderOrder {
+				if strings.HasPrefix(mt, prefix+"/") {
+					return mt, encoders[mt]
+				}
+			}
+		}
+	}
+	return contentType, encoders[contentType]
+}
+
+// acceptRange is one media range from a parsed Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an HTTP Accept header into media ranges ordered by
+// descending quality value (RFC 7231 §5.3.2), preserving the header's
+// original order among ranges with equal quality.
+func parseAccept(accept string) []acceptRange {
+	if accept == "" {
+		return nil
+	}
+
+	parts := strings.Split(accept, ",")
+	ranges := make([]acceptRange, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mediaType := part
+		q := 1.0
+		if i := strings.IndexByte(part, ';'); i >= 0 {
+			mediaType = strings.TrimSpace(part[:i])
+			for _, param := range strings.Split(part[i+1:], ";") {
+				name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if ok && name == "q" {
+					if v, err := strconv.ParseFloat(value, 64); err == nil {
+						q = v
+					}
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return ranges
+}
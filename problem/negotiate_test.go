@@ -0,0 +1,57 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendProblemNegotiated(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "no Accept header defaults to json", accept: "", want: contentType},
+		{name: "explicit json", accept: "application/problem+json", want: contentType},
+		{name: "explicit xml", accept: "application/problem+xml", want: "application/problem+xml"},
+		{
+			name:   "quality values pick the higher one",
+			accept: "application/problem+json;q=0.5, application/problem+xml;q=0.9",
+			want:   "application/problem+xml",
+		},
+		{
+			name:   "q=0 rejects the only mentioned type, falls back to default",
+			accept: "application/problem+xml;q=0",
+			want:   contentType,
+		},
+		{
+			name:   "unmatched type falls back to default",
+			accept: "text/plain",
+			want:   contentType,
+		},
+		{
+			name:   "type wildcard matches a registered subtype",
+			accept: "application/*",
+			want:   contentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			if err := SendProblemNegotiated(w, r, http.StatusBadRequest, Problem{Title: "bad"}); err != nil {
+				t.Fatalf("SendProblemNegotiated: %v", err)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
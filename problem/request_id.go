@@ -0,0 +1,56 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// requestIDExtensionKey is the JSON member name used to carry the request
+// ID in problem responses sent via SendProblemForRequest. Change it with
+// SetRequestIDExtensionKey.
+var requestIDExtensionKey = "request_id"
+
+// SetRequestIDExtensionKey sets the JSON member name used by
+// SendProblemForRequest to carry the request ID. The default is
+// "request_id".
+func SetRequestIDExtensionKey(key string) {
+	requestIDExtensionKey = key
+}
+
+// RequestIDFunc extracts the ID of the current request, for example via
+// webapputil.RequestID. SendProblemForRequest calls it to obtain the
+// value for the request ID extension member. It defaults to a function
+// that always returns "", which disables the extension.
+var RequestIDFunc = func(r *http.Request) string { return "" }
+
+// SendProblemForRequest writes a problem json response like SendProblem,
+// additionally injecting the request ID returned by RequestIDFunc as an
+// RFC 7807 extension member (the "request_id" member by default; see
+// SetRequestIDExtensionKey). If problem's Instance member is empty, it is
+// populated with a "urn:request:<id>" URN. problem may be any struct that
+// embeds Problem; reflection is not required since the merge happens on
+// the encoded JSON.
+func SendProblemForRequest(w http.ResponseWriter, r *http.Request, statusCode int, problem interface{}) error {
+	reqID := RequestIDFunc(r)
+	if reqID == "" {
+		return SendProblem(w, statusCode, problem)
+	}
+
+	b, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	if s, _ := m["instance"].(string); s == "" {
+		m["instance"] = "urn:request:" + reqID
+	}
+	m[requestIDExtensionKey] = reqID
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(m)
+}
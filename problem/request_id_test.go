@@ -0,0 +1,59 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendProblemForRequest(t *testing.T) {
+	origFunc := RequestIDFunc
+	origKey := requestIDExtensionKey
+	t.Cleanup(func() {
+		RequestIDFunc = origFunc
+		requestIDExtensionKey = origKey
+	})
+
+	RequestIDFunc = func(r *http.Request) string { return "abc-123" }
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := SendProblemForRequest(w, r, http.StatusInternalServerError, Problem{Title: "boom"}); err != nil {
+		t.Fatalf("SendProblemForRequest: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+
+	if got := body["request_id"]; got != "abc-123" {
+		t.Errorf("request_id = %v, want %q", got, "abc-123")
+	}
+	if got := body["instance"]; got != "urn:request:abc-123" {
+		t.Errorf("instance = %v, want %q", got, "urn:request:abc-123")
+	}
+}
+
+func TestSendProblemForRequestNoRequestID(t *testing.T) {
+	origFunc := RequestIDFunc
+	t.Cleanup(func() { RequestIDFunc = origFunc })
+	RequestIDFunc = func(r *http.Request) string { return "" }
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := SendProblemForRequest(w, r, http.StatusInternalServerError, Problem{Title: "boom"}); err != nil {
+		t.Fatalf("SendProblemForRequest: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	if _, ok := body["request_id"]; ok {
+		t.Errorf("request_id present in body = %v, want absent when RequestIDFunc returns \"\"", body)
+	}
+}
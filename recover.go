@@ -0,0 +1,96 @@
+package webapputil
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/hnakamur/webapputil/problem"
+)
+
+// Logger is the logging interface used by RecoverMiddleware.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type recoverConfig struct {
+	logger       Logger
+	includeStack bool
+	buildProblem func(r *http.Request, v interface{}) interface{}
+}
+
+// RecoverOption configures RecoverMiddleware.
+type RecoverOption func(*recoverConfig)
+
+// WithRecoverLogger sets the logger used to log recovered panics. It
+// defaults to the standard library's log package.
+func WithRecoverLogger(logger Logger) RecoverOption {
+	return func(c *recoverConfig) { c.logger = logger }
+}
+
+// WithRecoverStack controls whether the panic value and stack trace are
+// included in the problem response's Detail field. It is off by default,
+// since stack traces can leak implementation details to clients; enable
+// it only in development.
+func WithRecoverStack(include bool) RecoverOption {
+	return func(c *recoverConfig) { c.includeStack = include }
+}
+
+// WithRecoverProblem sets a hook that builds the value sent to
+// problem.SendProblemForRequest from the recovered panic value, letting callers
+// substitute a domain-specific problem struct, for example one embedding
+// problem.Problem with an "error_code" extension.
+func WithRecoverProblem(fn func(r *http.Request, v interface{}) interface{}) RecoverOption {
+	return func(c *recoverConfig) { c.buildProblem = fn }
+}
+
+// RecoverMiddleware wraps next, recovering any panic, logging it together
+// with the stack trace and request ID, and writing a 500
+// application/problem+json response in its place.
+func RecoverMiddleware(next http.Handler, opts ...RecoverOption) http.Handler {
+	c := &recoverConfig{
+		logger: log.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			stack := debug.Stack()
+			c.logger.Printf("recovered panic; request_id=%s panic=%v\n%s", requestIDOrEmpty(r), v, stack)
+
+			var prob interface{}
+			if c.buildProblem != nil {
+				prob = c.buildProblem(r, v)
+			} else {
+				p := problem.Problem{
+					Type:   "about:blank",
+					Title:  http.StatusText(http.StatusInternalServerError),
+					Status: http.StatusInternalServerError,
+				}
+				if c.includeStack {
+					p.Detail = fmt.Sprintf("panic: %v\n%s", v, stack)
+				}
+				prob = p
+			}
+			if err := problem.SendProblemForRequest(w, r, http.StatusInternalServerError, prob); err != nil {
+				c.logger.Printf("failed to send problem response; %v", err)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestIDOrEmpty returns the request ID set by RequestIDMiddleware, or
+// "" if none was set, unlike RequestID which panics in that case.
+func requestIDOrEmpty(r *http.Request) string {
+	v, _ := r.Context().Value(requestIDKey).(string)
+	return v
+}
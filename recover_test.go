@@ -0,0 +1,95 @@
+package webapputil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testLogger is a Logger that records formatted lines for assertions,
+// instead of writing to the standard logger.
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestRecoverMiddlewareWritesProblemResponse(t *testing.T) {
+	logger := &testLogger{}
+	panicky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	h := RecoverMiddleware(panicky, WithRecoverLogger(logger))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", got)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	if body["detail"] != nil {
+		t.Errorf("detail = %v, want absent without WithRecoverStack", body["detail"])
+	}
+	if len(logger.lines) == 0 {
+		t.Error("expected the panic to be logged, got no log lines")
+	}
+}
+
+func TestRecoverMiddlewareWithRecoverStack(t *testing.T) {
+	panicky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	h := RecoverMiddleware(panicky, WithRecoverLogger(&testLogger{}), WithRecoverStack(true))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	detail, _ := body["detail"].(string)
+	if !strings.Contains(detail, "boom") {
+		t.Errorf("detail = %q, want it to contain the panic value", detail)
+	}
+}
+
+func TestRecoverMiddlewareCarriesRequestID(t *testing.T) {
+	panicky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	h := RequestIDMiddleware(
+		RecoverMiddleware(panicky, WithRecoverLogger(&testLogger{})),
+		func(r *http.Request) string { return "req-42" },
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response body: %v", err)
+	}
+	if got := body["request_id"]; got != "req-42" {
+		t.Errorf("request_id = %v, want %q", got, "req-42")
+	}
+}
@@ -3,8 +3,17 @@ package webapputil
 import (
 	"context"
 	"net/http"
+
+	"github.com/hnakamur/webapputil/problem"
 )
 
+func init() {
+	// Wire problem.SendProblemForRequest to this package's request ID so
+	// that callers get request ID correlation for free, without having
+	// to remember a separate one-line setup step.
+	problem.RequestIDFunc = requestIDOrEmpty
+}
+
 // RequestIDFunc is a function type for generating a request ID.
 type RequestIDFunc func(req *http.Request) string
 